@@ -3,8 +3,13 @@ package otp
 import (
 	"crypto"
 	"crypto/hmac"
+	_ "crypto/sha1"   // register crypto.SHA1 for hmac.New
+	_ "crypto/sha256" // register crypto.SHA256 for hmac.New
+	_ "crypto/sha512" // register crypto.SHA512 for hmac.New
+	"crypto/subtle"
 	"encoding/base32"
 	"encoding/binary"
+	"fmt"
 	"math"
 	"net/url"
 	"strconv"
@@ -35,12 +40,29 @@ type OTP interface {
 	Counter() Counter
 	Hash() crypto.Hash
 	GenerateCode(key string, count int64, tokenLen int) (string, error)
-	VerifyCode(code, key string, skew, tokenLen int) bool
+
+	// GenerateRaw returns the 31-bit dynamically truncated HMAC value for
+	// count, before any digit or alphabet encoding is applied, so callers
+	// can implement custom encodings (Yubico modhex, base32, etc.) without
+	// reimplementing HMAC + dynamic truncation.
+	GenerateRaw(key string, count int64) (uint32, error)
+
+	// VerifyCode checks code against the counter window
+	// [current-(skew-1), current+(skew-1)]; a skew of 0 or 1 checks only current.
+	// It returns the counter value that produced the match so HOTP callers can
+	// persist it (see RFC 4226 section 7.2 resynchronization) and an error if
+	// the secret could not be decoded or a code could not be generated.
+	VerifyCode(code, key string, skew, tokenLen int) (ok bool, matchedCounter int64, err error)
 }
 
+// SteamAlphabet is the character set used by Steam Guard codes, as consumed
+// by NewSteamOTP.
+const SteamAlphabet = "23456789BCDFGHJKMNPQRTVWXY"
+
 type HOTP struct {
 	counter  Counter
 	hashAlgo crypto.Hash
+	alphabet string
 }
 
 func (h *HOTP) Counter() Counter {
@@ -51,16 +73,30 @@ func (h *HOTP) Hash() crypto.Hash {
 	return h.hashAlgo
 }
 
-func (h *HOTP) GenerateCode(key string, count int64, tokenLen int) (string, error) {
+func (h *HOTP) GenerateRaw(key string, count int64) (uint32, error) {
 	b, err := base32.StdEncoding.DecodeString(strings.ToUpper(key))
 
 	if err != nil {
-		return "", err
+		return 0, err
 	}
 
 	hmacResult := genKey(h.Hash(), b, count)
 
-	code := strconv.Itoa(truncate(hmacResult) % int(math.Pow10(tokenLen)))
+	return uint32(truncate(hmacResult)), nil
+}
+
+func (h *HOTP) GenerateCode(key string, count int64, tokenLen int) (string, error) {
+	raw, err := h.GenerateRaw(key, count)
+
+	if err != nil {
+		return "", err
+	}
+
+	if h.alphabet != "" {
+		return encodeAlphabet(raw, h.alphabet, tokenLen), nil
+	}
+
+	code := strconv.Itoa(int(raw) % int(math.Pow10(tokenLen)))
 
 	// pad the string on the left if necessary
 	if len(code) < tokenLen {
@@ -70,24 +106,62 @@ func (h *HOTP) GenerateCode(key string, count int64, tokenLen int) (string, erro
 	return code, nil
 }
 
-func (h *HOTP) VerifyCode(code, key string, skew, tokenLen int) bool {
+// encodeAlphabet repeatedly divides raw by len(alphabet), emitting one
+// character per digit, matching the Steam Guard code encoding.
+func encodeAlphabet(raw uint32, alphabet string, length int) string {
+	base := uint32(len(alphabet))
+	chars := make([]byte, length)
+
+	for i := 0; i < length; i++ {
+		chars[i] = alphabet[raw%base]
+		raw /= base
+	}
+
+	return string(chars)
+}
+
+func (h *HOTP) VerifyCode(code, key string, skew, tokenLen int) (bool, int64, error) {
 	currentCount := h.counter.Count()
-	currentCode, _ := h.GenerateCode(key, currentCount, tokenLen)
 
-	if currentCode == code {
-		return true
+	currentCode, err := h.GenerateCode(key, currentCount, tokenLen)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if constantTimeEqual(currentCode, code) {
+		return true, currentCount, nil
 	}
 
 	for i := 1; i < skew; i++ {
-		behind, _ := h.GenerateCode(key, currentCount-int64(i), tokenLen)
-		ahead, _ := h.GenerateCode(key, currentCount+int64(i), tokenLen)
+		behind, err := h.GenerateCode(key, currentCount-int64(i), tokenLen)
+		if err != nil {
+			return false, 0, err
+		}
 
-		if behind == code || ahead == code {
-			return true
+		if constantTimeEqual(behind, code) {
+			return true, currentCount - int64(i), nil
+		}
+
+		ahead, err := h.GenerateCode(key, currentCount+int64(i), tokenLen)
+		if err != nil {
+			return false, 0, err
+		}
+
+		if constantTimeEqual(ahead, code) {
+			return true, currentCount + int64(i), nil
 		}
 	}
 
-	return false
+	return false, 0, nil
+}
+
+// constantTimeEqual compares two OTP codes in constant time to avoid leaking
+// how many leading digits matched through response timing.
+func constantTimeEqual(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
 }
 
 func truncate(b []byte) int {
@@ -121,6 +195,46 @@ func DefaultTOTP() OTP {
 	return NewTOTP(crypto.SHA1, DefaultInterval, 0)
 }
 
+// StaticCounter is a Counter that always returns a fixed value, used for
+// HOTP secrets provisioned from an explicit starting counter (for example
+// one parsed from an otpauth:// URI via ParseAuthURL).
+type StaticCounter int64
+
+func (c StaticCounter) Count() int64 {
+	return int64(c)
+}
+
+func NewHOTP(hash crypto.Hash, counter int64) OTP {
+	return &HOTP{
+		counter:  StaticCounter(counter),
+		hashAlgo: hash,
+	}
+}
+
+// NewHOTPWithAlphabet returns an HOTP that encodes codes by repeatedly
+// dividing the truncated HMAC value by len(alphabet) and emitting one
+// character per digit from alphabet, instead of the usual base-10 encoding.
+func NewHOTPWithAlphabet(hash crypto.Hash, counter int64, alphabet string) OTP {
+	return &HOTP{
+		counter:  StaticCounter(counter),
+		hashAlgo: hash,
+		alphabet: alphabet,
+	}
+}
+
+// NewSteamOTP returns a time-based OTP producing Steam Guard codes (5
+// characters when GenerateCode is called with tokenLen 5), using
+// SteamAlphabet and the interval Steam's mobile app uses.
+func NewSteamOTP() OTP {
+	return &HOTP{
+		counter: &TimeCounter{
+			Interval: DefaultInterval,
+		},
+		hashAlgo: crypto.SHA1,
+		alphabet: SteamAlphabet,
+	}
+}
+
 type AuthURL struct {
 	Type      string
 	Label     string
@@ -133,15 +247,131 @@ type AuthURL struct {
 }
 
 func (o *AuthURL) String() string {
+	label := o.Label
+	if o.Issuer != "" {
+		label = o.Issuer + ":" + label
+	}
+
 	uri := &url.URL{
-		Scheme:   "otpauth",
+		Scheme:   OtpAuthScheme,
 		Host:     o.Type,
-		Path:     o.Label,
+		Path:     "/" + label,
 		RawQuery: o.values().Encode(),
 	}
 	return uri.String()
 }
 
+// ParseAuthURL parses an otpauth:// URI, such as one decoded from a Google
+// Authenticator QR code, into an AuthURL. The label's "issuer:account" form
+// is split into Issuer and Label, with the issuer query parameter taking
+// precedence when both are present.
+func ParseAuthURL(s string) (*AuthURL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != OtpAuthScheme {
+		return nil, fmt.Errorf("otp: unsupported scheme %q", u.Scheme)
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+
+	issuer, account := "", label
+	if idx := strings.Index(label, ":"); idx >= 0 {
+		issuer, account = label[:idx], label[idx+1:]
+	}
+
+	q := u.Query()
+
+	if qIssuer := q.Get("issuer"); qIssuer != "" {
+		issuer = qIssuer
+	}
+
+	authURL := &AuthURL{
+		Type:      u.Host,
+		Label:     account,
+		Secret:    q.Get("secret"),
+		Issuer:    issuer,
+		Algorithm: q.Get("algorithm"),
+	}
+
+	for param, dst := range map[string]*int{
+		"counter": &authURL.Counter,
+		"digits":  &authURL.Digits,
+		"period":  &authURL.Period,
+	} {
+		v := q.Get(param)
+		if v == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("otp: invalid %s %q: %w", param, v, err)
+		}
+
+		*dst = n
+	}
+
+	return authURL, nil
+}
+
+// DefaultDigits is the token length assumed when an otpauth:// URI omits
+// the digits parameter.
+const DefaultDigits = 6
+
+// FromURL parses an otpauth:// URI and builds a fully configured OTP,
+// dispatching on the totp/hotp host and mapping the algorithm, digits,
+// period, and counter parameters. It returns the decoded secret and digit
+// count alongside the OTP, since OTP itself carries neither and callers
+// need tokenLen to drive GenerateCode/VerifyCode correctly.
+func FromURL(s string) (otp OTP, secret string, digits int, err error) {
+	authURL, err := ParseAuthURL(s)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	hash, err := parseAlgorithm(authURL.Algorithm)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	digits = authURL.Digits
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+
+	switch strings.ToLower(authURL.Type) {
+	case "totp":
+		period := authURL.Period
+		if period == 0 {
+			period = DefaultInterval
+		}
+
+		return NewTOTP(hash, period, 0), authURL.Secret, digits, nil
+	case "hotp":
+		return NewHOTP(hash, int64(authURL.Counter)), authURL.Secret, digits, nil
+	default:
+		return nil, "", 0, fmt.Errorf("otp: unsupported type %q", authURL.Type)
+	}
+}
+
+// parseAlgorithm maps an otpauth algorithm query parameter (e.g. "SHA1",
+// "SHA-256") to its crypto.Hash, defaulting to SHA1 when unset.
+func parseAlgorithm(name string) (crypto.Hash, error) {
+	switch strings.ToUpper(strings.ReplaceAll(name, "-", "")) {
+	case "", "SHA1":
+		return crypto.SHA1, nil
+	case "SHA256":
+		return crypto.SHA256, nil
+	case "SHA512":
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("otp: unsupported algorithm %q", name)
+	}
+}
+
 func (o *AuthURL) values() url.Values {
 	v := url.Values{}
 
@@ -0,0 +1,196 @@
+package otp
+
+import (
+	"crypto"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestHOTPVerifyCode(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	h := NewHOTP(crypto.SHA1, 100)
+
+	code, err := h.GenerateCode(secret, 102, 6)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+
+	ok, matched, err := h.VerifyCode(code, secret, 5, 6)
+	if err != nil {
+		t.Fatalf("VerifyCode: %v", err)
+	}
+	if !ok {
+		t.Fatalf("VerifyCode(%q) = false, want true", code)
+	}
+	if matched != 102 {
+		t.Fatalf("matchedCounter = %d, want 102", matched)
+	}
+
+	ok, _, err = h.VerifyCode("000000", secret, 5, 6)
+	if err != nil {
+		t.Fatalf("VerifyCode: %v", err)
+	}
+	if ok {
+		t.Fatal("VerifyCode matched an unrelated code")
+	}
+}
+
+func TestHOTPVerifyCodeInvalidSecret(t *testing.T) {
+	h := NewHOTP(crypto.SHA1, 0)
+
+	_, _, err := h.VerifyCode("123456", "not-base32!", 1, 6)
+	if err == nil {
+		t.Fatal("VerifyCode with malformed secret should return an error")
+	}
+}
+
+func TestAuthURLStringAndParseAuthURLRoundTrip(t *testing.T) {
+	original := &AuthURL{
+		Type:      "totp",
+		Label:     "alice@example.com",
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Issuer:    "Example Co",
+		Algorithm: "SHA1",
+		Digits:    6,
+		Period:    30,
+	}
+
+	s := original.String()
+
+	parsed, err := ParseAuthURL(s)
+	if err != nil {
+		t.Fatalf("ParseAuthURL: %v", err)
+	}
+
+	if parsed.Type != original.Type {
+		t.Errorf("Type = %q, want %q", parsed.Type, original.Type)
+	}
+	if parsed.Label != original.Label {
+		t.Errorf("Label = %q, want %q", parsed.Label, original.Label)
+	}
+	if parsed.Issuer != original.Issuer {
+		t.Errorf("Issuer = %q, want %q", parsed.Issuer, original.Issuer)
+	}
+	if parsed.Secret != original.Secret {
+		t.Errorf("Secret = %q, want %q", parsed.Secret, original.Secret)
+	}
+	if parsed.Digits != original.Digits {
+		t.Errorf("Digits = %d, want %d", parsed.Digits, original.Digits)
+	}
+	if parsed.Period != original.Period {
+		t.Errorf("Period = %d, want %d", parsed.Period, original.Period)
+	}
+}
+
+func TestFromURL(t *testing.T) {
+	o, secret, digits, err := FromURL("otpauth://hotp/Example%20Co:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Example+Co&counter=41&digits=8")
+	if err != nil {
+		t.Fatalf("FromURL: %v", err)
+	}
+
+	if secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("secret = %q, want JBSWY3DPEHPK3PXP", secret)
+	}
+	if digits != 8 {
+		t.Errorf("digits = %d, want 8", digits)
+	}
+
+	code, err := o.GenerateCode(secret, 42, digits)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+	if len(code) != 8 {
+		t.Fatalf("len(code) = %d, want 8", len(code))
+	}
+}
+
+func TestFromURLDefaultsDigits(t *testing.T) {
+	_, _, digits, err := FromURL("otpauth://totp/Example:alice@example.com?secret=JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("FromURL: %v", err)
+	}
+	if digits != DefaultDigits {
+		t.Fatalf("digits = %d, want default %d", digits, DefaultDigits)
+	}
+}
+
+func TestNewSteamOTPProducesAlphabetCodes(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	o := NewSteamOTP()
+
+	code, err := o.GenerateCode(secret, 42, 5)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+	if len(code) != 5 {
+		t.Fatalf("len(code) = %d, want 5", len(code))
+	}
+
+	for _, r := range code {
+		if !strings.ContainsRune(SteamAlphabet, r) {
+			t.Fatalf("code %q contains rune %q outside SteamAlphabet", code, r)
+		}
+	}
+}
+
+func TestNewHOTPWithAlphabet(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	o := NewHOTPWithAlphabet(crypto.SHA1, 0, SteamAlphabet)
+
+	code, err := o.GenerateCode(secret, 5, 5)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+	if len(code) != 5 {
+		t.Fatalf("len(code) = %d, want 5", len(code))
+	}
+
+	for _, r := range code {
+		if !strings.ContainsRune(SteamAlphabet, r) {
+			t.Fatalf("code %q contains rune %q outside SteamAlphabet", code, r)
+		}
+	}
+}
+
+func TestGenerateRawMatchesEncodedOutput(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+
+	steam := NewSteamOTP()
+
+	raw, err := steam.GenerateRaw(secret, 7)
+	if err != nil {
+		t.Fatalf("GenerateRaw: %v", err)
+	}
+
+	wantSteamCode := encodeAlphabet(raw, SteamAlphabet, 5)
+
+	gotSteamCode, err := steam.GenerateCode(secret, 7, 5)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+	if gotSteamCode != wantSteamCode {
+		t.Fatalf("GenerateCode = %q, want %q derived from GenerateRaw", gotSteamCode, wantSteamCode)
+	}
+
+	decimal := NewHOTP(crypto.SHA1, 0)
+
+	rawDecimal, err := decimal.GenerateRaw(secret, 7)
+	if err != nil {
+		t.Fatalf("GenerateRaw: %v", err)
+	}
+	if rawDecimal != raw {
+		t.Fatalf("GenerateRaw(SHA1, 7) = %d, want %d (same HMAC/truncation as the Steam OTP)", rawDecimal, raw)
+	}
+
+	wantDecimalCode := fmt.Sprintf("%06d", int(rawDecimal)%int(math.Pow10(6)))
+
+	gotDecimalCode, err := decimal.GenerateCode(secret, 7, 6)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+	if gotDecimalCode != wantDecimalCode {
+		t.Fatalf("GenerateCode = %q, want %q derived from GenerateRaw", gotDecimalCode, wantDecimalCode)
+	}
+}
@@ -0,0 +1,47 @@
+package otp
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// GenerateSecret returns a new cryptographically random secret, base32
+// encoded to nBytes worth of entropy. The result is suitable as the key
+// passed to HOTP.GenerateCode and HOTP.VerifyCode.
+func GenerateSecret(nBytes int) (string, error) {
+	b := make([]byte, nBytes)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.EncodeToString(b), nil
+}
+
+// Key bundles a provisioned secret with the AuthURL describing it, covering
+// the enrollment flow: generate a secret, show it as a QR code, and have the
+// user scan it into an authenticator app.
+type Key struct {
+	Secret  string
+	AuthURL *AuthURL
+}
+
+// NewKey generates a new nBytes secret and wraps it with authURL, filling in
+// authURL.Secret so that Key.QR encodes a complete otpauth:// URI.
+func NewKey(nBytes int, authURL AuthURL) (*Key, error) {
+	secret, err := GenerateSecret(nBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	authURL.Secret = secret
+
+	return &Key{Secret: secret, AuthURL: &authURL}, nil
+}
+
+// QR renders the key's otpauth:// URI as a size x size PNG-encoded QR code.
+func (k *Key) QR(size int) ([]byte, error) {
+	return qrcode.Encode(k.AuthURL.String(), qrcode.Medium, size)
+}
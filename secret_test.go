@@ -0,0 +1,77 @@
+package otp
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base32"
+	"testing"
+)
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret(20)
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+
+	decoded, err := base32.StdEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret is not valid base32: %v", err)
+	}
+	if len(decoded) != 20 {
+		t.Fatalf("decoded len = %d, want 20", len(decoded))
+	}
+
+	h := NewHOTP(crypto.SHA1, 0)
+	if _, err := h.GenerateCode(secret, 0, 6); err != nil {
+		t.Fatalf("GenerateCode with generated secret: %v", err)
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	key, err := NewKey(20, AuthURL{
+		Type:      "totp",
+		Label:     "alice@example.com",
+		Issuer:    "Example Co",
+		Algorithm: "SHA1",
+		Digits:    6,
+		Period:    30,
+	})
+	if err != nil {
+		t.Fatalf("NewKey: %v", err)
+	}
+
+	if key.Secret == "" {
+		t.Fatal("key.Secret is empty")
+	}
+	if key.AuthURL.Secret != key.Secret {
+		t.Fatalf("key.AuthURL.Secret = %q, want %q", key.AuthURL.Secret, key.Secret)
+	}
+}
+
+func TestKeyQR(t *testing.T) {
+	key, err := NewKey(20, AuthURL{
+		Type:      "totp",
+		Label:     "alice@example.com",
+		Issuer:    "Example Co",
+		Algorithm: "SHA1",
+		Digits:    6,
+		Period:    30,
+	})
+	if err != nil {
+		t.Fatalf("NewKey: %v", err)
+	}
+
+	png, err := key.QR(256)
+	if err != nil {
+		t.Fatalf("QR: %v", err)
+	}
+
+	if len(png) == 0 {
+		t.Fatal("QR returned an empty image")
+	}
+
+	pngMagic := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+	if !bytes.HasPrefix(png, pngMagic) {
+		t.Fatal("QR did not return a valid PNG")
+	}
+}
@@ -0,0 +1,104 @@
+package otp
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStoreLoadCounterUnset(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT counter FROM otp_counters WHERE user_id = \$1`).
+		WithArgs("user1").
+		WillReturnError(sql.ErrNoRows)
+
+	s := NewSQLStore(db, "")
+
+	counter, err := s.LoadCounter("user1")
+	if err != nil {
+		t.Fatalf("LoadCounter: %v", err)
+	}
+	if counter != -1 {
+		t.Fatalf("counter = %d, want -1", counter)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSQLStoreSaveCounterUpsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO otp_counters \(user_id, counter\) VALUES \(\$1, \$2\) ON CONFLICT \(user_id\) DO UPDATE SET counter = excluded\.counter`).
+		WithArgs("user1", int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s := NewSQLStore(db, "")
+
+	if err := s.SaveCounter("user1", 5); err != nil {
+		t.Fatalf("SaveCounter: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSQLStoreRecordAttemptAtomicUpsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO otp_counters \(user_id, attempts\) VALUES \(\$1, 1\) ON CONFLICT \(user_id\) DO UPDATE SET attempts = otp_counters\.attempts \+ 1 RETURNING attempts`).
+		WithArgs("user1").
+		WillReturnRows(sqlmock.NewRows([]string{"attempts"}).AddRow(3))
+
+	s := NewSQLStore(db, "")
+
+	attempts, err := s.RecordAttempt("user1")
+	if err != nil {
+		t.Fatalf("RecordAttempt: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSQLStoreResetAttempts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE otp_counters SET attempts = 0 WHERE user_id = \$1`).
+		WithArgs("user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s := NewSQLStore(db, "")
+
+	if err := s.ResetAttempts("user1"); err != nil {
+		t.Fatalf("ResetAttempts: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,228 @@
+package otp
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// Store persists the last-used HOTP counter and tracks verification attempts
+// per user, enabling the resynchronization and throttling behavior
+// described in RFC 4226 section 7.
+type Store interface {
+	// LoadCounter returns the last persisted counter for userID, or -1 if
+	// none has been saved yet (so the look-ahead window in Verifier.Verify
+	// starts at counter 0, a freshly-provisioned token's first code).
+	LoadCounter(userID string) (int64, error)
+
+	// SaveCounter persists counter as the last-used counter for userID.
+	SaveCounter(userID string, counter int64) error
+
+	// RecordAttempt records a verification attempt for userID and returns
+	// the number of consecutive attempts recorded so far, for throttling.
+	RecordAttempt(userID string) (attempts int, err error)
+
+	// ResetAttempts clears the attempt count for userID, called after a
+	// successful verification.
+	ResetAttempts(userID string) error
+}
+
+// memoryStore is an in-memory Store, useful for tests and single-instance
+// deployments. It is safe for concurrent use.
+type memoryStore struct {
+	mu       sync.Mutex
+	counters map[string]int64
+	attempts map[string]int
+}
+
+// NewMemoryStore returns a Store backed by an in-process map.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		counters: make(map[string]int64),
+		attempts: make(map[string]int),
+	}
+}
+
+func (m *memoryStore) LoadCounter(userID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter, ok := m.counters[userID]
+	if !ok {
+		return -1, nil
+	}
+
+	return counter, nil
+}
+
+func (m *memoryStore) SaveCounter(userID string, counter int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters[userID] = counter
+	return nil
+}
+
+func (m *memoryStore) RecordAttempt(userID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.attempts[userID]++
+	return m.attempts[userID], nil
+}
+
+func (m *memoryStore) ResetAttempts(userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, userID)
+	return nil
+}
+
+// SQLStore is a Store backed by a sql.DB targeting PostgreSQL — it uses
+// $N placeholders and "ON CONFLICT ... DO UPDATE ... RETURNING", neither of
+// which MySQL supports, and neither of which is guaranteed to be valid
+// syntax on other dialects. It expects a table of the shape:
+//
+//	CREATE TABLE otp_counters (
+//		user_id  TEXT PRIMARY KEY,
+//		counter  BIGINT NOT NULL DEFAULT -1,
+//		attempts INT NOT NULL DEFAULT 0
+//	)
+//
+// table is a trusted, operator-supplied identifier (not end-user input) and
+// is interpolated directly into the query since database/sql cannot
+// parameterize identifiers.
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore returns a Store backed by db, storing rows in table. If table
+// is empty it defaults to "otp_counters".
+func NewSQLStore(db *sql.DB, table string) *SQLStore {
+	if table == "" {
+		table = "otp_counters"
+	}
+
+	return &SQLStore{db: db, table: table}
+}
+
+func (s *SQLStore) LoadCounter(userID string) (int64, error) {
+	var counter int64
+
+	query := fmt.Sprintf("SELECT counter FROM %s WHERE user_id = $1", s.table)
+	err := s.db.QueryRow(query, userID).Scan(&counter)
+	if err == sql.ErrNoRows {
+		return -1, nil
+	}
+
+	return counter, err
+}
+
+func (s *SQLStore) SaveCounter(userID string, counter int64) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, counter) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET counter = excluded.counter
+	`, s.table)
+
+	_, err := s.db.Exec(query, userID, counter)
+	return err
+}
+
+// RecordAttempt upserts and increments the attempt count in a single
+// round trip via RETURNING, so concurrent callers for the same userID
+// (the brute-force scenario this throttle exists for) can't both read the
+// same pre-increment value.
+func (s *SQLStore) RecordAttempt(userID string) (int, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, attempts) VALUES ($1, 1)
+		ON CONFLICT (user_id) DO UPDATE SET attempts = %s.attempts + 1
+		RETURNING attempts
+	`, s.table, s.table)
+
+	var attempts int
+	err := s.db.QueryRow(query, userID).Scan(&attempts)
+	return attempts, err
+}
+
+func (s *SQLStore) ResetAttempts(userID string) error {
+	query := fmt.Sprintf("UPDATE %s SET attempts = 0 WHERE user_id = $1", s.table)
+	_, err := s.db.Exec(query, userID)
+	return err
+}
+
+// ErrTooManyAttempts is returned by Verifier.Verify once a user has exceeded
+// the configured MaxAttempts of consecutive failed verifications.
+var ErrTooManyAttempts = fmt.Errorf("otp: too many failed attempts")
+
+// Verifier wraps an OTP and a Store to provide RFC 4226 section 7.2
+// compliant HOTP verification: it rejects codes at or below the user's
+// last-used counter, advances the stored counter to the matched value on
+// success, and throttles after MaxAttempts consecutive failures.
+type Verifier struct {
+	OTP         OTP
+	Store       Store
+	TokenLen    int
+	LookAhead   int
+	MaxAttempts int
+}
+
+// NewVerifier returns a Verifier checking codes from otp against store,
+// looking ahead up to lookAhead counter values and throttling after
+// maxAttempts consecutive failures (0 disables throttling).
+func NewVerifier(otp OTP, store Store, tokenLen, lookAhead, maxAttempts int) *Verifier {
+	return &Verifier{
+		OTP:         otp,
+		Store:       store,
+		TokenLen:    tokenLen,
+		LookAhead:   lookAhead,
+		MaxAttempts: maxAttempts,
+	}
+}
+
+// Verify checks code for userID against secret. On success it persists the
+// matched counter so the next Verify call only accepts counters beyond it.
+func (v *Verifier) Verify(userID, code, secret string) (bool, error) {
+	if v.MaxAttempts > 0 {
+		attempts, err := v.Store.RecordAttempt(userID)
+		if err != nil {
+			return false, err
+		}
+
+		if attempts > v.MaxAttempts {
+			return false, ErrTooManyAttempts
+		}
+	}
+
+	lastCounter, err := v.Store.LoadCounter(userID)
+	if err != nil {
+		return false, err
+	}
+
+	lookAhead := v.LookAhead
+	if lookAhead <= 0 {
+		lookAhead = 1
+	}
+
+	for i := 1; i <= lookAhead; i++ {
+		candidate := lastCounter + int64(i)
+
+		expected, err := v.OTP.GenerateCode(secret, candidate, v.TokenLen)
+		if err != nil {
+			return false, err
+		}
+
+		if !constantTimeEqual(expected, code) {
+			continue
+		}
+
+		if err := v.Store.SaveCounter(userID, candidate); err != nil {
+			return false, err
+		}
+
+		return true, v.Store.ResetAttempts(userID)
+	}
+
+	return false, nil
+}
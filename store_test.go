@@ -0,0 +1,91 @@
+package otp
+
+import (
+	"crypto"
+	"testing"
+)
+
+func TestVerifierAcceptsFirstCodeFromFreshToken(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	h := NewHOTP(crypto.SHA1, 0)
+
+	code, err := h.GenerateCode(secret, 0, 6)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+
+	v := NewVerifier(h, NewMemoryStore(), 6, 3, 5)
+
+	ok, err := v.Verify("user1", code, secret)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !ok {
+		t.Fatal("Verify rejected a freshly-provisioned token's first code")
+	}
+}
+
+func TestVerifierRejectsReplayedCounter(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	h := NewHOTP(crypto.SHA1, 0)
+	store := NewMemoryStore()
+	v := NewVerifier(h, store, 6, 3, 5)
+
+	code, err := h.GenerateCode(secret, 0, 6)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+
+	if ok, err := v.Verify("user1", code, secret); err != nil || !ok {
+		t.Fatalf("first Verify = %v, %v, want true, nil", ok, err)
+	}
+
+	if ok, err := v.Verify("user1", code, secret); err != nil || ok {
+		t.Fatalf("replayed Verify = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestVerifierAdvancesWithinLookAheadWindow(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	h := NewHOTP(crypto.SHA1, 0)
+	store := NewMemoryStore()
+	v := NewVerifier(h, store, 6, 3, 5)
+
+	// Skip ahead two counters, inside the look-ahead window.
+	code, err := h.GenerateCode(secret, 2, 6)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+
+	ok, err := v.Verify("user1", code, secret)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !ok {
+		t.Fatal("Verify rejected a code within the look-ahead window")
+	}
+
+	counter, err := store.LoadCounter("user1")
+	if err != nil {
+		t.Fatalf("LoadCounter: %v", err)
+	}
+	if counter != 2 {
+		t.Fatalf("stored counter = %d, want 2", counter)
+	}
+}
+
+func TestVerifierThrottlesAfterMaxAttempts(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	h := NewHOTP(crypto.SHA1, 0)
+	v := NewVerifier(h, NewMemoryStore(), 6, 3, 2)
+
+	for i := 0; i < 2; i++ {
+		if ok, err := v.Verify("user1", "000000", secret); err != nil || ok {
+			t.Fatalf("Verify #%d = %v, %v, want false, nil", i, ok, err)
+		}
+	}
+
+	if _, err := v.Verify("user1", "000000", secret); err != ErrTooManyAttempts {
+		t.Fatalf("Verify after MaxAttempts = %v, want ErrTooManyAttempts", err)
+	}
+}